@@ -28,11 +28,13 @@ import (
 var (
 	source   string
 	generate string
+	against  string
 )
 
 func init() {
 	flag.StringVar(&source, "source", "-", "source path; required; stdin is TODO")
-	flag.StringVar(&generate, "generate", "graphql", "Output type, graphql or dgraph")
+	flag.StringVar(&generate, "generate", "graphql", "Output type, graphql, dgraph or diff")
+	flag.StringVar(&against, "against", "", "prior schema path; required when -generate=diff")
 	flag.Parse()
 }
 
@@ -46,6 +48,12 @@ func main() {
 		cwd, _ := os.Getwd()
 		panic(fmt.Errorf("Failed to read source %s (from %s): %w", source, cwd, err))
 	}
+
+	if generate == "diff" {
+		runDiff(string(schema))
+		return
+	}
+
 	handler, err := dschema.NewHandler(string(schema), false)
 	if err != nil {
 		panic(fmt.Errorf("Failed to init for length %d: %w", len(schema), err))
@@ -60,3 +68,29 @@ func main() {
 	}
 	fmt.Print(result)
 }
+
+// runDiff prints the Breaking/Dangerous/Safe changes between the schema at
+// -against and newSchema, one per line.
+func runDiff(newSchema string) {
+	if against == "" {
+		panic(fmt.Errorf("-against is required when -generate=diff"))
+	}
+	oldRaw, err := ioutil.ReadFile(against)
+	if err != nil {
+		cwd, _ := os.Getwd()
+		panic(fmt.Errorf("Failed to read against %s (from %s): %w", against, cwd, err))
+	}
+
+	oldSchema, errs := dschema.GenerateCompleteSchema(string(oldRaw), false)
+	if errs != nil {
+		panic(errs)
+	}
+	newSch, errs := dschema.GenerateCompleteSchema(newSchema, false)
+	if errs != nil {
+		panic(errs)
+	}
+
+	for _, change := range dschema.DiffSchema(oldSchema, newSch) {
+		fmt.Printf("%s: %s\n", change.Severity, change.Description)
+	}
+}
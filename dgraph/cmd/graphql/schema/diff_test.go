@@ -0,0 +1,127 @@
+/*
+ * Copyright 2021 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schema
+
+import (
+	"testing"
+
+	"github.com/vektah/gqlparser/ast"
+)
+
+func mustGenSchema(t *testing.T, input string) *ast.Schema {
+	t.Helper()
+	sch, errs := GenerateCompleteSchema(input, false)
+	if errs != nil {
+		t.Fatalf("GenerateCompleteSchema(%q): %v", input, errs)
+	}
+	return sch
+}
+
+func TestAreEqualSchemaIgnoresDeclarationOrder(t *testing.T) {
+	s1 := mustGenSchema(t, `
+		type Post { id: ID! title: String score: Int }
+	`)
+	s2 := mustGenSchema(t, `
+		type Post { score: Int id: ID! title: String }
+	`)
+
+	if !AreEqualSchema(s1, s1) {
+		t.Error("schema should be equal to itself")
+	}
+	if !AreEqualSchema(s1, s2) {
+		t.Error("schemas differing only in field declaration order should be equal")
+	}
+}
+
+func TestAreEqualSchemaDetectsDirectiveChange(t *testing.T) {
+	s1 := mustGenSchema(t, `type Post { id: ID! title: String }`)
+	s2 := mustGenSchema(t, `type Post { id: ID! title: String @search(by: ["regexp"]) }`)
+
+	if AreEqualSchema(s1, s2) {
+		t.Error("adding a directive application should make schemas unequal")
+	}
+}
+
+func severityFor(t *testing.T, changes []SchemaChange, description string) ChangeSeverity {
+	t.Helper()
+	for _, c := range changes {
+		if c.Description == description {
+			return c.Severity
+		}
+	}
+	t.Fatalf("no change found with description %q in %v", description, changes)
+	return ""
+}
+
+func TestDiffSchemaOutputFieldSeverity(t *testing.T) {
+	old := mustGenSchema(t, `type Post { id: ID! title: String }`)
+
+	addedField := mustGenSchema(t, `type Post { id: ID! title: String views: Int! }`)
+	changes := DiffSchema(old, addedField)
+	if got := severityFor(t, changes, "Post.views was added"); got != Safe {
+		t.Errorf("adding a non-null output field: got %s, want %s", got, Safe)
+	}
+
+	tightened := mustGenSchema(t, `type Post { id: ID! title: String! }`)
+	changes = DiffSchema(old, tightened)
+	if got := severityFor(t, changes, "Post.title became non-null (String -> String!)"); got != Safe {
+		t.Errorf("tightening an output field to non-null: got %s, want %s", got, Safe)
+	}
+
+	loosened := mustGenSchema(t, `type Post { id: ID! title: String! }`)
+	changes = DiffSchema(loosened, old)
+	if got := severityFor(t, changes, "Post.title became nullable (String! -> String)"); got != Breaking {
+		t.Errorf("loosening an output field to nullable: got %s, want %s", got, Breaking)
+	}
+}
+
+func TestDiffSchemaInputFieldSeverity(t *testing.T) {
+	old := mustGenSchema(t, `type Post { id: ID! title: String }`)
+	tightened := mustGenSchema(t, `type Post { id: ID! title: String! }`)
+
+	changes := DiffSchema(old, tightened)
+	if got := severityFor(t, changes, "PostInput.title became non-null (String -> String!)"); got != Breaking {
+		t.Errorf("tightening an input field to non-null: got %s, want %s", got, Breaking)
+	}
+
+	changes = DiffSchema(tightened, old)
+	if got := severityFor(t, changes, "PostInput.title became nullable (String! -> String)"); got != Safe {
+		t.Errorf("loosening an input field to nullable: got %s, want %s", got, Safe)
+	}
+}
+
+func TestDiffSchemaFieldDirectiveChangeIsDangerous(t *testing.T) {
+	old := mustGenSchema(t, `type Post { id: ID! title: String }`)
+	searchable := mustGenSchema(t, `type Post { id: ID! title: String @search(by: ["regexp"]) }`)
+
+	changes := DiffSchema(old, searchable)
+	got := severityFor(t, changes,
+		`Post.title directives changed from [] to [@search(by:["regexp"])]`)
+	if got != Dangerous {
+		t.Errorf("adding a directive to an existing field: got %s, want %s", got, Dangerous)
+	}
+}
+
+func TestDiffSchemaTypeRemovalIsBreaking(t *testing.T) {
+	old := mustGenSchema(t, `type Post { id: ID! }`)
+	removed := mustGenSchema(t, ``)
+
+	changes := DiffSchema(old, removed)
+	if got := severityFor(t, changes, "Type Post was removed"); got != Breaking {
+		t.Errorf("removing a type: got %s, want %s", got, Breaking)
+	}
+}
@@ -0,0 +1,101 @@
+/*
+ * Copyright 2021 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schema
+
+import (
+	"testing"
+
+	"github.com/vektah/gqlparser/ast"
+	"github.com/vektah/gqlparser/parser"
+	"github.com/vektah/gqlparser/validator"
+)
+
+// validateGenerated re-parses and re-validates the SDL a generated schema
+// stringifies to, the way a client would load it - it's the surface that
+// catches a generated reference to a type that's invalid or doesn't exist.
+func validateGenerated(t *testing.T, sch *ast.Schema) {
+	t.Helper()
+
+	doc, err := parser.ParseSchema(&ast.Source{Input: Stringify(sch)})
+	if err != nil {
+		t.Fatalf("generated SDL doesn't parse: %v", err)
+	}
+	if _, gqlErr := validator.ValidateSchemaDocument(doc); gqlErr != nil {
+		t.Fatalf("generated SDL doesn't validate: %v", gqlErr)
+	}
+}
+
+func TestInterfaceTypedFieldGeneratesRef(t *testing.T) {
+	sch := mustGenSchema(t, `
+		interface Character {
+			id: ID!
+			name: String
+		}
+		type Human implements Character {
+			id: ID!
+			name: String
+			bestFriend: Character
+		}
+	`)
+
+	validateGenerated(t, sch)
+
+	input, ok := sch.Types["HumanInput"]
+	if !ok {
+		t.Fatal("HumanInput wasn't generated")
+	}
+	bestFriend := input.Fields.ForName("bestFriend")
+	if bestFriend == nil {
+		t.Fatal("HumanInput.bestFriend wasn't generated")
+	}
+	if got := bestFriend.Type.Name(); got != "CharacterRef" {
+		t.Errorf("HumanInput.bestFriend type = %s, want CharacterRef", got)
+	}
+	if sch.Types["CharacterRef"] == nil {
+		t.Error("CharacterRef wasn't generated")
+	}
+}
+
+func TestUnionTypedFieldIsDroppedFromInput(t *testing.T) {
+	sch := mustGenSchema(t, `
+		type Cat { id: ID! name: String }
+		type Dog { id: ID! name: String }
+		union Pet = Cat | Dog
+		type Human {
+			id: ID!
+			name: String
+			pet: Pet
+		}
+	`)
+
+	validateGenerated(t, sch)
+
+	input := sch.Types["HumanInput"]
+	if input.Fields.ForName("pet") != nil {
+		t.Error("HumanInput.pet should be dropped - a union has no field common to all its members")
+	}
+}
+
+func TestInterfaceFieldDirectivesAreValidated(t *testing.T) {
+	_, errs := GenerateCompleteSchema(`
+		interface Character { id: ID! age: Int @search(by: ["regexp"]) }
+	`, false)
+
+	if errs == nil {
+		t.Fatal("expected @search(by: [\"regexp\"]) on an Int field to be rejected, even on an interface")
+	}
+}
@@ -0,0 +1,50 @@
+/*
+ * Copyright 2021 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schema
+
+import "testing"
+
+func TestRelayConnectionImplementsNodeOnlyWithIDNamedID(t *testing.T) {
+	sch := mustGenSchema(t, `
+		type Person @relayConnection { uid: ID! name: String }
+		type Thing @relayConnection { id: ID! name: String }
+	`)
+
+	validateGenerated(t, sch)
+
+	person := sch.Types["Person"]
+	for _, iface := range person.Interfaces {
+		if iface == nodeInterfaceName {
+			t.Error("Person has no field named id, so it shouldn't implement Node")
+		}
+	}
+
+	thing := sch.Types["Thing"]
+	found := false
+	for _, iface := range thing.Interfaces {
+		if iface == nodeInterfaceName {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("Thing.id is named id, so it should implement Node")
+	}
+
+	if sch.Types["PersonConnection"] == nil || sch.Types["PersonEdge"] == nil {
+		t.Error("Person should still get a Connection/Edge pair even without Node")
+	}
+}
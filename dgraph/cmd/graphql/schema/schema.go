@@ -17,7 +17,9 @@
 package schema
 
 import (
+	"encoding/base64"
 	"fmt"
+	"reflect"
 	"sort"
 	"strings"
 
@@ -45,18 +47,103 @@ type args struct {
 	nonNull bool
 }
 
-type directive struct {
-	name string
-	args ast.ArgumentDefinitionList
+// GeneratedFields collects the pieces a directive's Extend hook contributes
+// to the types generated for a defn - the registry dispatches these into
+// the right generated Input/Filter/Update type and into the Query/Mutation
+// field lists, so a directive's schema-generation behaviour lives in one
+// place rather than being hardcoded into genInputType, genFilterType, etc.
+type GeneratedFields struct {
+	Input    ast.FieldList
+	Filter   ast.FieldList
+	Update   ast.FieldList
+	Query    ast.FieldList
+	Mutation ast.FieldList
+}
+
+// DirectiveValidateFunc checks a single usage of a directive - dir, applied
+// to fld on typ - against the rest of the (not yet fully validated) schema
+// doc, and returns a user facing error if the usage is invalid.
+type DirectiveValidateFunc func(
+	doc *ast.SchemaDocument, typ *ast.Definition, fld *ast.FieldDefinition, dir *ast.Directive) *gqlerror.Error
+
+// DirectiveExtendFunc contributes fields to the types generated for defn.
+// genFilterType calls it once for every object, interface and union type,
+// and extendGeneratedTypes calls it once more for every object type,
+// regardless of how many fields of that type carry the directive -
+// implementations are expected to walk defn.Fields themselves to find the
+// fields they care about.
+type DirectiveExtendFunc func(schema *ast.Schema, defn *ast.Definition) GeneratedFields
+
+// Directive is a directive dgraph's GraphQL layer understands: its argument
+// and location shape, how a usage of it is validated, and how it
+// contributes to the schema generated for the type it's used on.  Third
+// parties add to the set dgraph supports with RegisterDirective, rather
+// than the rest of this package growing a string compare against the
+// directive's name for every place it's relevant.
+type Directive struct {
+	Name      string
+	Args      ast.ArgumentDefinitionList
+	Locations []ast.DirectiveLocation
+	Validate  DirectiveValidateFunc
+	Extend    DirectiveExtendFunc
 }
 
 const (
 	inverseName   = "hasInverse"
 	inverseFldArg = "field"
+
+	searchName  = "search"
+	searchByArg = "by"
+
+	relayConnectionName = "relayConnection"
+
+	nodeInterfaceName = "Node"
+	pageInfoName      = "PageInfo"
 )
 
+// searchOp describes one of the predicates @search(by: [...]) can ask for:
+// the suffix appended to the field's name to build the generated filter
+// field, and whether that field's argument is a list of the field's type
+// (as with "in") rather than a single value.
+type searchOp struct {
+	suffix string
+	isList bool
+}
+
+// searchOps maps a @search "by" value to the filter field it generates.
+// Numeric/DateTime fields use eq/lt/le/gt/ge/in, strings add regexp,
+// anyofterms and alloftext - the same comparators and term/full-text
+// indices dgraph already exposes over DQL.
+var searchOps = map[string]searchOp{
+	"eq":         {suffix: "Eq"},
+	"lt":         {suffix: "Lt"},
+	"le":         {suffix: "Le"},
+	"gt":         {suffix: "Gt"},
+	"ge":         {suffix: "Ge"},
+	"in":         {suffix: "In", isList: true},
+	"regexp":     {suffix: "Regexp"},
+	"anyofterms": {suffix: "AnyofTerms"},
+	"alloftext":  {suffix: "Alloftext"},
+}
+
+// searchableBy maps a scalar's name to the @search "by" values valid for
+// it - numeric and DateTime fields get the comparators, String fields get
+// the term/full-text/regexp indices.  A scalar absent from this map (ID,
+// Boolean) can't be searched at all yet.
+var searchableBy = map[string]map[string]bool{
+	"Int":      {"eq": true, "lt": true, "le": true, "gt": true, "ge": true, "in": true},
+	"Float":    {"eq": true, "lt": true, "le": true, "gt": true, "ge": true, "in": true},
+	"DateTime": {"eq": true, "lt": true, "le": true, "gt": true, "ge": true, "in": true},
+	"String":   {"regexp": true, "anyofterms": true, "alloftext": true},
+}
+
 var schRules []schRule
 
+// directiveRegistry is the set of directives dgraph's GraphQL layer
+// understands, in registration order.  It's built up by RegisterDirective -
+// see the init() below for the directives built into this package.
+var directiveRegistry []*Directive
+
 var supportedScalars = []scalar{
 	{name: "ID", dgraphType: "uid"},
 	{name: "Boolean", dgraphType: "bool"},
@@ -65,10 +152,52 @@ var supportedScalars = []scalar{
 	{name: "String", dgraphType: "string"},
 	{name: "DateTime", dgraphType: "dateTime"}}
 
-var supportedDirectives = []directive{
-	{name: inverseName,
-		args: ast.ArgumentDefinitionList{
-			{Name: inverseFldArg, Type: &ast.Type{NamedType: "String", NonNull: true}}}}}
+func init() {
+	RegisterDirective(Directive{
+		Name: inverseName,
+		Args: ast.ArgumentDefinitionList{
+			{Name: inverseFldArg, Type: &ast.Type{NamedType: "String", NonNull: true}}},
+		Locations: []ast.DirectiveLocation{ast.LocationField},
+		Validate:  validateHasInverse,
+	})
+
+	RegisterDirective(Directive{
+		Name: searchName,
+		Args: ast.ArgumentDefinitionList{
+			{Name: searchByArg, Type: &ast.Type{
+				Elem: &ast.Type{NamedType: "String", NonNull: true}}}},
+		Locations: []ast.DirectiveLocation{ast.LocationField},
+		Validate:  validateSearch,
+		Extend:    genSearchFilterFields,
+	})
+
+	// relayConnection is a type level switch, not a per-field contribution,
+	// so unlike hasInverse/search it has no Validate/Extend here - its
+	// effect on query generation is applied directly in
+	// GenerateCompleteSchema.  It's still registered so it shows up in the
+	// generated directive definitions and AddDirectives adds it to the doc.
+	RegisterDirective(Directive{
+		Name:      relayConnectionName,
+		Locations: []ast.DirectiveLocation{ast.LocationObject},
+	})
+}
+
+// RegisterDirective adds d to the set of directives dgraph's GraphQL layer
+// understands.  Third parties hook new directives - and their validation
+// and schema-generation behaviour - into schema generation by calling this
+// rather than patching the directive dispatch spread through this package.
+func RegisterDirective(d Directive) {
+	directiveRegistry = append(directiveRegistry, &d)
+}
+
+func directiveByName(name string) *Directive {
+	for _, d := range directiveRegistry {
+		if d.Name == name {
+			return d
+		}
+	}
+	return nil
+}
 
 // AddScalars adds all the supported scalars in the schema.
 func AddScalars(doc *ast.SchemaDocument) {
@@ -79,8 +208,8 @@ func AddScalars(doc *ast.SchemaDocument) {
 
 // AddDirectives add all the supported directives to schema.
 func AddDirectives(doc *ast.SchemaDocument) {
-	for _, d := range supportedDirectives {
-		addDirective(d, []ast.DirectiveLocation{ast.LocationField}, doc)
+	for _, d := range directiveRegistry {
+		addDirective(d, doc)
 	}
 }
 
@@ -92,15 +221,43 @@ func addScalar(s scalar, doc *ast.SchemaDocument) {
 	)
 }
 
-func addDirective(d directive, locations []ast.DirectiveLocation, doc *ast.SchemaDocument) {
+func addDirective(d *Directive, doc *ast.SchemaDocument) {
 	doc.Directives = append(doc.Directives, &ast.DirectiveDefinition{
-		Name:      d.name,
-		Locations: locations,
-		Arguments: d.args,
+		Name:      d.Name,
+		Locations: d.Locations,
+		Arguments: d.Args,
 	})
 }
 
-// AddRule adds a new schema rule to the global array schRules.
+// validateDirectiveUsages walks doc looking for uses of registered
+// directives and runs each one's Validate function, so a third party's
+// directive gets the same validation pass as the built in ones.
+func validateDirectiveUsages(doc *ast.SchemaDocument) gqlerror.List {
+	var errs []*gqlerror.Error
+
+	for _, typ := range doc.Definitions {
+		if typ.Kind != ast.Object && typ.Kind != ast.Interface {
+			continue
+		}
+		for _, fld := range typ.Fields {
+			for _, dir := range fld.Directives {
+				d := directiveByName(dir.Name)
+				if d == nil || d.Validate == nil {
+					continue
+				}
+				if gqlErr := d.Validate(doc, typ, fld, dir); gqlErr != nil {
+					errs = append(errs, gqlErr)
+				}
+			}
+		}
+	}
+
+	return errs
+}
+
+// AddRule adds a new schema rule to the global array schRules.  Rules that
+// reject a misspelled type or field name should use Suggest to offer a
+// "did you mean" correction, the way validateHasInverse does.
 func AddRule(name string, f schRuleFunc) {
 	schRules = append(schRules, schRule{
 		name:        name,
@@ -118,12 +275,16 @@ func ValidateSchema(schema *ast.SchemaDocument) gqlerror.List {
 		}
 	}
 
+	errs = append(errs, validateDirectiveUsages(schema)...)
+
 	return errs
 }
 
 // GenerateCompleteSchema generates all the required query/mutation/update functions
-// for all the types mentioned the the schema.
-func GenerateCompleteSchema(inputSchema string) (*ast.Schema, gqlerror.List) {
+// for all the types mentioned the the schema.  If relayConnections is true,
+// every type gets Relay style Connection/Edge query generation; otherwise
+// that's opt-in per type via @relayConnection.
+func GenerateCompleteSchema(inputSchema string, relayConnections bool) (*ast.Schema, gqlerror.List) {
 
 	doc, gqlErr := parser.ParseSchema(&ast.Source{Input: inputSchema})
 	if gqlErr != nil {
@@ -158,21 +319,55 @@ func GenerateCompleteSchema(inputSchema string) (*ast.Schema, gqlerror.List) {
 		Fields:      make([]*ast.FieldDefinition, 0),
 	}
 
+	relayEnabled := false
+
 	for _, defn := range schema.Types {
 		if defn.Kind == ast.Object {
 			extenderMap[defn.Name+"Input"] = genInputType(schema, defn)
-			extenderMap[defn.Name+"Ref"] = genRefType(defn)
+			extenderMap[defn.Name+"Ref"] = genRefType(schema, defn)
 			extenderMap[defn.Name+"Update"] = genUpdateType(schema, defn)
-			extenderMap[defn.Name+"Filter"] = genFilterType(defn)
+			extenderMap[defn.Name+"Filter"] = genFilterType(schema, defn)
 			extenderMap["Add"+defn.Name+"Payload"] = genAddResultType(defn)
 			extenderMap["Update"+defn.Name+"Payload"] = genUpdResultType(defn)
 			extenderMap["Delete"+defn.Name+"Payload"] = genDelResultType(defn)
 
-			schema.Query.Fields = append(schema.Query.Fields, addQueryType(defn)...)
+			orderable := getOrderableFields(schema, defn)
+			if len(orderable) > 0 {
+				extenderMap[defn.Name+"Orderable"] = genOrderableType(defn, orderable)
+				extenderMap[defn.Name+"Order"] = genOrderType(defn)
+			}
+
+			relay := relayConnections || defn.Directives.ForName(relayConnectionName) != nil
+			if relay {
+				idFld := getIDField(schema, defn)
+				if len(idFld) > 0 && idFld[0].Name == "id" {
+					defn.Interfaces = append(defn.Interfaces, nodeInterfaceName)
+					relayEnabled = true
+				}
+				extenderMap[defn.Name+"Connection"] = genConnectionType(defn)
+				extenderMap[defn.Name+"Edge"] = genEdgeType(defn)
+			}
+
+			schema.Query.Fields = append(
+				schema.Query.Fields, addQueryType(defn, len(orderable) > 0, relay)...)
 			schema.Mutation.Fields = append(schema.Mutation.Fields, addMutationType(defn)...)
+
+			extendGeneratedTypes(schema, defn, extenderMap)
+		} else if defn.Kind == ast.Interface {
+			extenderMap[defn.Name+"Filter"] = genFilterType(schema, defn)
+			extenderMap[defn.Name+"Ref"] = genRefType(schema, defn)
+			schema.Query.Fields = append(schema.Query.Fields, createInterfaceQryFld(defn))
+		} else if defn.Kind == ast.Union {
+			extenderMap[defn.Name+"Filter"] = genFilterType(schema, defn)
+			schema.Query.Fields = append(schema.Query.Fields, createUnionQryFld(defn))
 		}
 	}
 
+	if relayEnabled {
+		extenderMap[nodeInterfaceName] = genNodeInterface()
+		extenderMap[pageInfoName] = genPageInfoType()
+	}
+
 	for name, extType := range extenderMap {
 		schema.Types[name] = extType
 	}
@@ -180,65 +375,408 @@ func GenerateCompleteSchema(inputSchema string) (*ast.Schema, gqlerror.List) {
 	return schema, nil
 }
 
-// AreEqualSchema checks if sch1 and sch2 are the same schema.
-func AreEqualSchema(sch1, sch2 *ast.Schema) bool {
-	return AreEqualQuery(sch1.Query, sch2.Query) &&
-		AreEqualMutation(sch1.Mutation, sch2.Mutation) &&
-		AreEqualTypes(sch1.Types, sch2.Types)
+// introArg is the canonical form of an argument - on a field or a
+// directive application - used by AreEqualSchema/DiffSchema: just enough
+// to tell whether two schemas agree on it, independent of the order
+// things were declared in.
+type introArg struct {
+	Name    string
+	Type    string
+	Default string
+}
+
+// introField is the canonical form of a field (or input field) definition.
+type introField struct {
+	Name       string
+	Type       string
+	Default    string
+	Args       []introArg
+	Directives []string
 }
 
-// AreEqualQuery checks if query blocks qry1, qry2 are same.
-func AreEqualQuery(qry1, qry2 *ast.Definition) bool {
-	return AreEqualFields(qry1.Fields, qry2.Fields)
+// introType is the canonical form of everything introspection would tell
+// you about a named type: its fields and their args, enum values,
+// implemented interfaces and (for unions) member types.
+type introType struct {
+	Kind          ast.DefinitionKind
+	Fields        []introField
+	EnumValues    []string
+	Interfaces    []string
+	PossibleTypes []string
 }
 
-// AreEqualMutation checks if mutation blocks mut1, mut2 are same.
-func AreEqualMutation(mut1, mut2 *ast.Definition) bool {
-	return AreEqualFields(mut1.Fields, mut2.Fields)
+// introspect walks sch the way a client's introspection query would,
+// and produces a form that's stable under re-ordering of types, fields,
+// arguments and directives, so two schemas built from differently-ordered
+// input can still compare equal.
+func introspect(sch *ast.Schema) map[string]introType {
+	types := make(map[string]introType, len(sch.Types)+2)
+	for name, defn := range sch.Types {
+		types[name] = introspectType(defn)
+	}
+	if sch.Query != nil {
+		types["Query"] = introspectType(sch.Query)
+	}
+	if sch.Mutation != nil {
+		types["Mutation"] = introspectType(sch.Mutation)
+	}
+	return types
 }
 
-// AreEqualTypes checks if types typ1, typ2 are same.
-func AreEqualTypes(typ1, typ2 map[string]*ast.Definition) bool {
-	for name, def := range typ1 {
-		val, ok := typ2[name]
+func introspectType(defn *ast.Definition) introType {
+	it := introType{Kind: defn.Kind}
 
-		if !ok || def.Kind != val.Kind {
-			return false
+	for _, fld := range defn.Fields {
+		if strings.HasPrefix(fld.Name, "__") {
+			continue
 		}
+		it.Fields = append(it.Fields, introspectField(fld))
+	}
+	sort.Slice(it.Fields, func(i, j int) bool { return it.Fields[i].Name < it.Fields[j].Name })
 
-		if !AreEqualFields(def.Fields, val.Fields) {
-			return false
+	for _, val := range defn.EnumValues {
+		if strings.HasPrefix(val.Name, "__") {
+			continue
 		}
+		it.EnumValues = append(it.EnumValues, val.Name)
 	}
+	sort.Strings(it.EnumValues)
+
+	it.Interfaces = append([]string{}, defn.Interfaces...)
+	sort.Strings(it.Interfaces)
+
+	it.PossibleTypes = append([]string{}, defn.Types...)
+	sort.Strings(it.PossibleTypes)
 
-	return true
+	return it
 }
 
-// AreEqualFields checks if fieldlist flds1, flds2 are same.
-func AreEqualFields(flds1, flds2 ast.FieldList) bool {
-	fldDict := make(map[string]*ast.FieldDefinition)
+func introspectField(fld *ast.FieldDefinition) introField {
+	f := introField{Name: fld.Name, Type: fld.Type.String(), Default: valueString(fld.DefaultValue)}
 
-	for _, fld := range flds1 {
-		fldDict[fld.Name] = fld
+	for _, arg := range fld.Arguments {
+		f.Args = append(f.Args, introArg{
+			Name:    arg.Name,
+			Type:    arg.Type.String(),
+			Default: valueString(arg.DefaultValue),
+		})
 	}
+	sort.Slice(f.Args, func(i, j int) bool { return f.Args[i].Name < f.Args[j].Name })
 
-	for _, fld := range flds2 {
+	for _, dir := range fld.Directives {
+		f.Directives = append(f.Directives, genDirectiveString(dir))
+	}
+	sort.Strings(f.Directives)
 
-		if strings.HasPrefix(fld.Name, "__") {
-			continue
+	return f
+}
+
+// valueString renders a default value for comparison; a nil value - "no
+// default" - renders as "".
+func valueString(v *ast.Value) string {
+	if v == nil {
+		return ""
+	}
+	return v.String()
+}
+
+// AreEqualSchema checks if sch1 and sch2 describe the same schema - same
+// types, fields, arguments, defaults, directives applied to fields, enum
+// values, interfaces and union members - regardless of declaration order.
+// Directives applied to a type itself are not compared.
+func AreEqualSchema(sch1, sch2 *ast.Schema) bool {
+	return reflect.DeepEqual(introspect(sch1), introspect(sch2))
+}
+
+// ChangeSeverity classifies a SchemaChange by how safe it is to deploy.
+type ChangeSeverity string
+
+const (
+	// Breaking changes can make previously valid queries/mutations fail -
+	// e.g. removing a field or type, or narrowing an argument's type.
+	Breaking ChangeSeverity = "Breaking"
+	// Dangerous changes are unlikely to break existing queries, but change
+	// behaviour clients may have been relying on - e.g. the set of
+	// directives applied to a field changing.
+	Dangerous ChangeSeverity = "Dangerous"
+	// Safe changes can't break an existing query - e.g. adding an optional
+	// argument or a new type.
+	Safe ChangeSeverity = "Safe"
+)
+
+// SchemaChange is one difference DiffSchema found between two schemas.
+type SchemaChange struct {
+	Severity    ChangeSeverity
+	Description string
+}
+
+// DiffSchema compares old and new and classifies every difference it
+// finds as Breaking, Dangerous or Safe, so operators can gate a schema
+// deployment on whether it breaks existing clients.
+func DiffSchema(old, new *ast.Schema) []SchemaChange {
+	oldTypes := introspect(old)
+	newTypes := introspect(new)
+
+	var names []string
+	seen := make(map[string]bool)
+	for name := range oldTypes {
+		names = append(names, name)
+		seen[name] = true
+	}
+	for name := range newTypes {
+		if !seen[name] {
+			names = append(names, name)
 		}
-		val, ok := fldDict[fld.Name]
+	}
+	sort.Strings(names)
+
+	var changes []SchemaChange
+	for _, name := range names {
+		o, oldOk := oldTypes[name]
+		n, newOk := newTypes[name]
+
+		switch {
+		case oldOk && !newOk:
+			changes = append(changes, SchemaChange{Breaking, fmt.Sprintf("Type %s was removed", name)})
+		case !oldOk && newOk:
+			changes = append(changes, SchemaChange{Safe, fmt.Sprintf("Type %s was added", name)})
+		default:
+			changes = append(changes, diffType(name, o, n)...)
+		}
+	}
 
-		if !ok {
-			return false
+	return changes
+}
+
+func diffType(name string, o, n introType) []SchemaChange {
+	if o.Kind != n.Kind {
+		return []SchemaChange{{
+			Breaking,
+			fmt.Sprintf("Type %s changed kind from %s to %s", name, o.Kind, n.Kind),
+		}}
+	}
+
+	isInput := o.Kind == ast.InputObject
+
+	var changes []SchemaChange
+	changes = append(changes, diffFields(name, o.Fields, n.Fields, isInput)...)
+	changes = append(changes, diffSet(name, "enum value", o.EnumValues, n.EnumValues)...)
+	changes = append(changes, diffSet(name, "implemented interface", o.Interfaces, n.Interfaces)...)
+	changes = append(changes, diffSet(name, "union member", o.PossibleTypes, n.PossibleTypes)...)
+	return changes
+}
+
+// diffSet compares two unordered sets of names (enum values, interfaces a
+// type implements, union members) - removing one is Breaking, adding one
+// is Safe.
+func diffSet(typeName, label string, oldVals, newVals []string) []SchemaChange {
+	oldSet, newSet := toSet(oldVals), toSet(newVals)
+
+	var changes []SchemaChange
+	for _, v := range oldVals {
+		if !newSet[v] {
+			changes = append(changes,
+				SchemaChange{Breaking, fmt.Sprintf("%s: %s %s was removed", typeName, label, v)})
 		}
+	}
+	for _, v := range newVals {
+		if !oldSet[v] {
+			changes = append(changes,
+				SchemaChange{Safe, fmt.Sprintf("%s: %s %s was added", typeName, label, v)})
+		}
+	}
+	return changes
+}
+
+func toSet(vals []string) map[string]bool {
+	set := make(map[string]bool, len(vals))
+	for _, v := range vals {
+		set[v] = true
+	}
+	return set
+}
+
+// diffFields compares the field lists of one type between old and new. The
+// severity rules differ for input fields (InputObject - isInput true) versus
+// output fields (Object/Interface/Union/Query/Mutation - isInput false),
+// since an input field is supplied by the caller constructing/updating a
+// value, while an output field is supplied by the server: see addedSeverity
+// and diffFieldTypeString.
+func diffFields(typeName string, oldFlds, newFlds []introField, isInput bool) []SchemaChange {
+	oldByName := make(map[string]introField, len(oldFlds))
+	for _, f := range oldFlds {
+		oldByName[f.Name] = f
+	}
+	newByName := make(map[string]introField, len(newFlds))
+	for _, f := range newFlds {
+		newByName[f.Name] = f
+	}
+
+	var names []string
+	seen := make(map[string]bool)
+	for _, f := range oldFlds {
+		names = append(names, f.Name)
+		seen[f.Name] = true
+	}
+	for _, f := range newFlds {
+		if !seen[f.Name] {
+			names = append(names, f.Name)
+		}
+	}
+	sort.Strings(names)
+
+	var changes []SchemaChange
+	for _, name := range names {
+		fldPath := typeName + "." + name
+		o, oldOk := oldByName[name]
+		n, newOk := newByName[name]
+
+		switch {
+		case oldOk && !newOk:
+			changes = append(changes, SchemaChange{Breaking, fldPath + " was removed"})
+		case !oldOk && newOk:
+			changes = append(changes,
+				SchemaChange{addedSeverity(n.Type, n.Default, isInput), fldPath + " was added"})
+		default:
+			changes = append(changes, diffFieldTypeString(fldPath, o.Type, n.Type, isInput)...)
+			changes = append(changes, diffArgs(fldPath, o.Args, n.Args)...)
+			changes = append(changes, diffFieldDirectives(fldPath, o.Directives, n.Directives)...)
+		}
+	}
+	return changes
+}
+
+// diffFieldDirectives reports a changed set of directive applications on a
+// field as Dangerous: it can't change the GraphQL contract the way a type
+// or nullability change can, so it's never Breaking, but a directive like
+// @search or @dgraph changes how the field behaves in ways an existing
+// client may be relying on, so it's not Safe either.
+func diffFieldDirectives(fldPath string, oldDirs, newDirs []string) []SchemaChange {
+	if reflect.DeepEqual(oldDirs, newDirs) {
+		return nil
+	}
+	return []SchemaChange{{
+		Dangerous,
+		fmt.Sprintf("%s directives changed from %v to %v", fldPath, oldDirs, newDirs),
+	}}
+}
+
+func diffArgs(fldPath string, oldArgs, newArgs []introArg) []SchemaChange {
+	oldByName := make(map[string]introArg, len(oldArgs))
+	for _, a := range oldArgs {
+		oldByName[a.Name] = a
+	}
+	newByName := make(map[string]introArg, len(newArgs))
+	for _, a := range newArgs {
+		newByName[a.Name] = a
+	}
 
-		if genFieldString(fld) != genFieldString(val) {
-			return false
+	var names []string
+	seen := make(map[string]bool)
+	for _, a := range oldArgs {
+		names = append(names, a.Name)
+		seen[a.Name] = true
+	}
+	for _, a := range newArgs {
+		if !seen[a.Name] {
+			names = append(names, a.Name)
+		}
+	}
+	sort.Strings(names)
+
+	var changes []SchemaChange
+	for _, name := range names {
+		argPath := fmt.Sprintf("%s(%s:)", fldPath, name)
+		o, oldOk := oldByName[name]
+		n, newOk := newByName[name]
+
+		switch {
+		case oldOk && !newOk:
+			changes = append(changes, SchemaChange{Breaking, argPath + " was removed"})
+		case !oldOk && newOk:
+			// An argument is always caller-supplied, same as an input field.
+			changes = append(changes,
+				SchemaChange{addedSeverity(n.Type, n.Default, true), argPath + " was added"})
+		case o.Type != n.Type:
+			// For an argument, loosening (non-null -> nullable) is Safe and
+			// tightening (nullable -> non-null) is Breaking - the same as an
+			// input field and the opposite of an output field, since callers
+			// are the ones supplying it.
+			changes = append(changes, diffArgTypeString(argPath, o.Type, n.Type))
 		}
 	}
+	return changes
+}
+
+// addedSeverity classifies a newly added field or argument. An output
+// field (isInput false) can never break an existing query by being added -
+// nothing selects it unless a client asks for it. An input field or
+// argument (isInput true) is supplied by the caller, so adding one that's
+// required with no default breaks every caller that doesn't already send it.
+func addedSeverity(typ, def string, isInput bool) ChangeSeverity {
+	if isInput && isNonNullType(typ) && def == "" {
+		return Breaking
+	}
+	return Safe
+}
 
-	return true
+func isNonNullType(typ string) bool {
+	return strings.HasSuffix(typ, "!")
+}
+
+// diffFieldTypeString classifies a field's type change. A change to the
+// named type itself is always Breaking. Otherwise the safe direction of a
+// nullability change depends on who supplies the value: for an input field
+// (isInput true) the caller supplies it, so tightening (nullable -> non-null)
+// can break a caller that omitted it, while loosening is always safe - the
+// same reasoning diffArgTypeString applies to arguments. For an output
+// field the server supplies it, so it's the other way round: tightening
+// only strengthens a guarantee any existing client already coded against
+// (safe), while loosening can hand null to code that assumed a value was
+// always present (breaking).
+func diffFieldTypeString(fldPath, oldType, newType string, isInput bool) []SchemaChange {
+	if oldType == newType {
+		return nil
+	}
+
+	oldBase := strings.TrimSuffix(oldType, "!")
+	newBase := strings.TrimSuffix(newType, "!")
+	if oldBase != newBase {
+		return []SchemaChange{{
+			Breaking, fmt.Sprintf("%s changed type from %s to %s", fldPath, oldType, newType)}}
+	}
+
+	becameNonNull := !isNonNullType(oldType) && isNonNullType(newType)
+	tighteningSeverity, looseningSeverity := Safe, Breaking
+	if isInput {
+		tighteningSeverity, looseningSeverity = Breaking, Safe
+	}
+
+	if becameNonNull {
+		return []SchemaChange{{
+			tighteningSeverity, fmt.Sprintf("%s became non-null (%s -> %s)", fldPath, oldType, newType)}}
+	}
+	return []SchemaChange{{
+		looseningSeverity, fmt.Sprintf("%s became nullable (%s -> %s)", fldPath, oldType, newType)}}
+}
+
+// diffArgTypeString is diffFieldTypeString's counterpart for arguments,
+// applying its input field rules - tightening (nullable -> non-null) is
+// Breaking for a caller, loosening is Safe.
+func diffArgTypeString(argPath, oldType, newType string) SchemaChange {
+	oldBase := strings.TrimSuffix(oldType, "!")
+	newBase := strings.TrimSuffix(newType, "!")
+	if oldBase != newBase {
+		return SchemaChange{
+			Breaking, fmt.Sprintf("%s changed type from %s to %s", argPath, oldType, newType)}
+	}
+
+	if isNonNullType(oldType) && !isNonNullType(newType) {
+		return SchemaChange{
+			Safe, fmt.Sprintf("%s became nullable (%s -> %s)", argPath, oldType, newType)}
+	}
+	return SchemaChange{
+		Breaking, fmt.Sprintf("%s became non-null (%s -> %s)", argPath, oldType, newType)}
 }
 
 func genInputType(schema *ast.Schema, defn *ast.Definition) *ast.Definition {
@@ -249,11 +787,11 @@ func genInputType(schema *ast.Schema, defn *ast.Definition) *ast.Definition {
 	}
 }
 
-func genRefType(defn *ast.Definition) *ast.Definition {
+func genRefType(schema *ast.Schema, defn *ast.Definition) *ast.Definition {
 	return &ast.Definition{
 		Kind:   ast.InputObject,
 		Name:   defn.Name + "Ref",
-		Fields: getIDField(defn),
+		Fields: getIDField(schema, defn),
 	}
 }
 
@@ -271,11 +809,221 @@ func genUpdateType(schema *ast.Schema, defn *ast.Definition) *ast.Definition {
 	return updDefn
 }
 
-func genFilterType(defn *ast.Definition) *ast.Definition {
+// fieldsIncludingInterfaces returns defn's own fields plus any field
+// declared on an interface defn implements that defn doesn't redeclare
+// itself - so a type implementing an interface doesn't have to repeat the
+// interface's fields for them to show up in the generated
+// Input/Update/Ref/Filter types.
+func fieldsIncludingInterfaces(schema *ast.Schema, defn *ast.Definition) ast.FieldList {
+	seen := make(map[string]bool, len(defn.Fields))
+	flds := make(ast.FieldList, 0, len(defn.Fields))
+
+	for _, fld := range defn.Fields {
+		seen[fld.Name] = true
+		flds = append(flds, fld)
+	}
+
+	for _, ifaceName := range defn.Interfaces {
+		iface := schema.Types[ifaceName]
+		if iface == nil {
+			continue
+		}
+		for _, fld := range iface.Fields {
+			if !seen[fld.Name] {
+				seen[fld.Name] = true
+				flds = append(flds, fld)
+			}
+		}
+	}
+
+	return flds
+}
+
+// getOrderableFields returns the names of defn's fields (including those
+// inherited from implemented interfaces) that can be sorted on - the
+// scalar, non-list fields other than its ID.
+func getOrderableFields(schema *ast.Schema, defn *ast.Definition) []string {
+	var names []string
+	for _, fld := range fieldsIncludingInterfaces(schema, defn) {
+		if isIDField(defn, fld) || fld.Type.NamedType == "" {
+			continue
+		}
+		if isScalar(fld.Type.Name()) {
+			names = append(names, fld.Name)
+		}
+	}
+	return names
+}
+
+func isScalar(typeName string) bool {
+	for _, s := range supportedScalars {
+		if s.name == typeName {
+			return true
+		}
+	}
+	return false
+}
+
+func genOrderableType(defn *ast.Definition, orderable []string) *ast.Definition {
+	var vals ast.EnumValueList
+	for _, name := range orderable {
+		vals = append(vals, &ast.EnumValueDefinition{Name: name})
+	}
+
+	return &ast.Definition{
+		Kind:       ast.Enum,
+		Name:       defn.Name + "Orderable",
+		EnumValues: vals,
+	}
+}
+
+func genOrderType(defn *ast.Definition) *ast.Definition {
+	orderableName := defn.Name + "Orderable"
+	return &ast.Definition{
+		Kind: ast.InputObject,
+		Name: defn.Name + "Order",
+		Fields: ast.FieldList{
+			&ast.FieldDefinition{Name: "asc", Type: &ast.Type{NamedType: orderableName}},
+			&ast.FieldDefinition{Name: "desc", Type: &ast.Type{NamedType: orderableName}},
+		},
+	}
+}
+
+func genFilterType(schema *ast.Schema, defn *ast.Definition) *ast.Definition {
+	var flds ast.FieldList
+	for _, d := range directiveRegistry {
+		if d.Extend == nil {
+			continue
+		}
+		flds = append(flds, d.Extend(schema, defn).Filter...)
+	}
+
+	// Every filter can combine its predicates with and/or/not - that's
+	// structural to filtering, not something any one directive contributes.
+	filterName := defn.Name + "Filter"
+	flds = append(flds,
+		&ast.FieldDefinition{Name: "and", Type: &ast.Type{NamedType: filterName}},
+		&ast.FieldDefinition{Name: "or", Type: &ast.Type{NamedType: filterName}},
+		&ast.FieldDefinition{Name: "not", Type: &ast.Type{NamedType: filterName}},
+	)
+
 	return &ast.Definition{
 		Kind:   ast.InputObject,
-		Name:   defn.Name + "Filter",
-		Fields: getFilterField(),
+		Name:   filterName,
+		Fields: flds,
+	}
+}
+
+// genSearchFilterFields is the Directive.Extend for @search: for every
+// field of defn carrying a @search(by: [...]), it builds a filter
+// predicate field per requested comparator - e.g. @search(by: ["gt", "le"])
+// on an Int field age generates ageGt and ageLe filter fields.
+func genSearchFilterFields(schema *ast.Schema, defn *ast.Definition) GeneratedFields {
+	var flds ast.FieldList
+
+	for _, fld := range fieldsIncludingInterfaces(schema, defn) {
+		dir := fld.Directives.ForName(searchName)
+		if dir == nil {
+			continue
+		}
+		flds = append(flds, genSearchFieldsFor(fld, dir)...)
+	}
+
+	return GeneratedFields{Filter: flds}
+}
+
+func genSearchFieldsFor(fld *ast.FieldDefinition, dir *ast.Directive) ast.FieldList {
+	var flds ast.FieldList
+
+	for _, by := range searchByValues(dir) {
+		op, ok := searchOps[by]
+		if !ok {
+			continue
+		}
+
+		typ := &ast.Type{NamedType: fld.Type.Name()}
+		if op.isList {
+			typ = &ast.Type{Elem: &ast.Type{NamedType: fld.Type.Name(), NonNull: true}}
+		}
+
+		flds = append(flds, &ast.FieldDefinition{Name: fld.Name + op.suffix, Type: typ})
+	}
+
+	return flds
+}
+
+func searchByValues(dir *ast.Directive) []string {
+	arg := dir.Arguments.ForName(searchByArg)
+	if arg == nil || arg.Value == nil {
+		return nil
+	}
+
+	var vals []string
+	for _, child := range arg.Value.Children {
+		vals = append(vals, child.Value.Raw)
+	}
+	return vals
+}
+
+// validateSearch is the Directive.Validate for @search: it rejects a "by"
+// value that doesn't apply to the field's scalar type, e.g.
+// @search(by: ["regexp"]) on an Int field, so an unsupported combination
+// is caught here rather than silently producing a nonsensical filter
+// field in genSearchFieldsFor.
+func validateSearch(
+	doc *ast.SchemaDocument, typ *ast.Definition, fld *ast.FieldDefinition, dir *ast.Directive) *gqlerror.Error {
+
+	allowed, ok := searchableBy[fld.Type.Name()]
+	if !ok {
+		return gqlerror.ErrorPosf(
+			dir.Position, "%s.%s: @search is not supported on type %s.",
+			typ.Name, fld.Name, fld.Type.Name())
+	}
+
+	for _, by := range searchByValues(dir) {
+		if _, ok := searchOps[by]; !ok {
+			return gqlerror.ErrorPosf(
+				dir.Position, "%s.%s: @search(by: ...) %q is not a recognised search option.",
+				typ.Name, fld.Name, by)
+		}
+		if !allowed[by] {
+			return gqlerror.ErrorPosf(
+				dir.Position, "%s.%s: @search(by: ...) %q is not valid for type %s.",
+				typ.Name, fld.Name, by, fld.Type.Name())
+		}
+	}
+
+	return nil
+}
+
+// extendGeneratedTypes runs every registered directive's Extend hook for
+// defn and folds the fields it contributes into the types already built
+// for defn in extenderMap, and into schema.Query/schema.Mutation.  Filter
+// contributions are handled by genFilterType, since that type is built
+// from the registry alone rather than having a hardcoded base.
+func extendGeneratedTypes(
+	schema *ast.Schema, defn *ast.Definition, extenderMap map[string]*ast.Definition) {
+
+	for _, d := range directiveRegistry {
+		if d.Extend == nil {
+			continue
+		}
+
+		gen := d.Extend(schema, defn)
+		if len(gen.Input) > 0 {
+			extenderMap[defn.Name+"Input"].Fields =
+				append(extenderMap[defn.Name+"Input"].Fields, gen.Input...)
+		}
+		if len(gen.Update) > 0 {
+			extenderMap[defn.Name+"Update"].Fields =
+				append(extenderMap[defn.Name+"Update"].Fields, gen.Update...)
+		}
+		if len(gen.Query) > 0 {
+			schema.Query.Fields = append(schema.Query.Fields, gen.Query...)
+		}
+		if len(gen.Mutation) > 0 {
+			schema.Mutation.Fields = append(schema.Mutation.Fields, gen.Mutation...)
+		}
 	}
 }
 
@@ -347,7 +1095,176 @@ func createGetFld(defn *ast.Definition) *ast.FieldDefinition {
 	}
 }
 
-func createQryFld(defn *ast.Definition) *ast.FieldDefinition {
+// genNodeInterface builds the Relay Node interface: a type in
+// @relayConnection mode implements it when its ID field is named id,
+// so get<Type> results can be used wherever a Node is expected. Types
+// whose ID field has a different name still get a Connection/Edge pair,
+// but can't satisfy Node's id: ID! requirement, so they're left out.
+func genNodeInterface() *ast.Definition {
+	return &ast.Definition{
+		Kind: ast.Interface,
+		Name: nodeInterfaceName,
+		Fields: ast.FieldList{
+			&ast.FieldDefinition{Name: "id", Type: &ast.Type{NamedType: "ID", NonNull: true}},
+		},
+	}
+}
+
+// genPageInfoType builds the Relay PageInfo type, shared by every
+// <Type>Connection in the schema.
+func genPageInfoType() *ast.Definition {
+	return &ast.Definition{
+		Kind: ast.Object,
+		Name: pageInfoName,
+		Fields: ast.FieldList{
+			&ast.FieldDefinition{
+				Name: "hasNextPage", Type: &ast.Type{NamedType: "Boolean", NonNull: true}},
+			&ast.FieldDefinition{
+				Name: "hasPreviousPage", Type: &ast.Type{NamedType: "Boolean", NonNull: true}},
+			&ast.FieldDefinition{Name: "startCursor", Type: &ast.Type{NamedType: "String"}},
+			&ast.FieldDefinition{Name: "endCursor", Type: &ast.Type{NamedType: "String"}},
+		},
+	}
+}
+
+func genConnectionType(defn *ast.Definition) *ast.Definition {
+	return &ast.Definition{
+		Kind: ast.Object,
+		Name: defn.Name + "Connection",
+		Fields: ast.FieldList{
+			&ast.FieldDefinition{
+				Name: "edges",
+				Type: &ast.Type{
+					NonNull: true,
+					Elem:    &ast.Type{NamedType: defn.Name + "Edge", NonNull: true},
+				},
+			},
+			&ast.FieldDefinition{
+				Name: "pageInfo", Type: &ast.Type{NamedType: pageInfoName, NonNull: true}},
+			&ast.FieldDefinition{Name: "totalCount", Type: &ast.Type{NamedType: "Int"}},
+		},
+	}
+}
+
+func genEdgeType(defn *ast.Definition) *ast.Definition {
+	return &ast.Definition{
+		Kind: ast.Object,
+		Name: defn.Name + "Edge",
+		Fields: ast.FieldList{
+			&ast.FieldDefinition{Name: "node", Type: &ast.Type{NamedType: defn.Name, NonNull: true}},
+			&ast.FieldDefinition{Name: "cursor", Type: &ast.Type{NamedType: "String", NonNull: true}},
+		},
+	}
+}
+
+// createRelayQryFld is the @relayConnection counterpart of createQryFld: it
+// returns a <Type>Connection instead of a plain list, and takes cursor
+// based first/last/before/after pagination arguments instead of
+// first/offset.
+func createRelayQryFld(defn *ast.Definition, hasOrder bool) *ast.FieldDefinition {
+	args := []*ast.ArgumentDefinition{
+		&ast.ArgumentDefinition{Name: "filter", Type: &ast.Type{NamedType: defn.Name + "Filter"}},
+	}
+
+	if hasOrder {
+		args = append(args, &ast.ArgumentDefinition{
+			Name: "order", Type: &ast.Type{NamedType: defn.Name + "Order"},
+		})
+	}
+
+	args = append(args,
+		&ast.ArgumentDefinition{Name: "first", Type: &ast.Type{NamedType: "Int"}},
+		&ast.ArgumentDefinition{Name: "after", Type: &ast.Type{NamedType: "String"}},
+		&ast.ArgumentDefinition{Name: "last", Type: &ast.Type{NamedType: "Int"}},
+		&ast.ArgumentDefinition{Name: "before", Type: &ast.Type{NamedType: "String"}},
+	)
+
+	return &ast.FieldDefinition{
+		Description: "Query " + defn.Name + " as a Relay connection",
+		Name:        "query" + defn.Name,
+		Type:        &ast.Type{NamedType: defn.Name + "Connection", NonNull: true},
+		Arguments:   args,
+	}
+}
+
+// EncodeCursor builds an opaque Relay cursor out of a node's type, uid and
+// its value for the field the connection is ordered on, so the cursor
+// keeps meaning across process restarts without needing a server side
+// cache of offsets.
+func EncodeCursor(typeName, uid, orderKey string) string {
+	raw := strings.Join([]string{typeName, uid, orderKey}, ":")
+	return base64.StdEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeCursor reverses EncodeCursor.
+func DecodeCursor(cursor string) (typeName, uid, orderKey string, err error) {
+	raw, err := base64.StdEncoding.DecodeString(cursor)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	parts := strings.SplitN(string(raw), ":", 3)
+	if len(parts) != 3 {
+		return "", "", "", fmt.Errorf("malformed cursor %q", cursor)
+	}
+
+	return parts[0], parts[1], parts[2], nil
+}
+
+// createInterfaceQryFld builds query<Iface>(filter: <Iface>Filter): [<Iface>!]!
+// - interfaces don't get the full query<Type> treatment (no order/paging),
+// just enough to fetch the set of implementors matching a filter.
+func createInterfaceQryFld(defn *ast.Definition) *ast.FieldDefinition {
+	return &ast.FieldDefinition{
+		Description: "Query " + defn.Name,
+		Name:        "query" + defn.Name,
+		Type: &ast.Type{
+			NonNull: true,
+			Elem:    &ast.Type{NamedType: defn.Name, NonNull: true},
+		},
+		Arguments: []*ast.ArgumentDefinition{
+			&ast.ArgumentDefinition{Name: "filter", Type: &ast.Type{NamedType: defn.Name + "Filter"}},
+		},
+	}
+}
+
+// createUnionQryFld builds query<Union>(filter: <Union>Filter): [<Union>!]!
+func createUnionQryFld(defn *ast.Definition) *ast.FieldDefinition {
+	return &ast.FieldDefinition{
+		Description: "Query " + defn.Name,
+		Name:        "query" + defn.Name,
+		Type: &ast.Type{
+			NonNull: true,
+			Elem:    &ast.Type{NamedType: defn.Name, NonNull: true},
+		},
+		Arguments: []*ast.ArgumentDefinition{
+			&ast.ArgumentDefinition{Name: "filter", Type: &ast.Type{NamedType: defn.Name + "Filter"}},
+		},
+	}
+}
+
+func createQryFld(defn *ast.Definition, hasOrder bool) *ast.FieldDefinition {
+	args := []*ast.ArgumentDefinition{
+		&ast.ArgumentDefinition{
+			Name: "filter",
+			Type: &ast.Type{
+				NamedType: defn.Name + "Filter",
+			},
+		},
+	}
+
+	if hasOrder {
+		args = append(args, &ast.ArgumentDefinition{
+			Name: "order",
+			Type: &ast.Type{NamedType: defn.Name + "Order"},
+		})
+	}
+
+	args = append(args,
+		&ast.ArgumentDefinition{Name: "first", Type: &ast.Type{NamedType: "Int"}},
+		&ast.ArgumentDefinition{Name: "offset", Type: &ast.Type{NamedType: "Int"}},
+	)
+
 	return &ast.FieldDefinition{
 		Description: "Query " + defn.Name,
 		Name:        "query" + defn.Name,
@@ -358,21 +1275,17 @@ func createQryFld(defn *ast.Definition) *ast.FieldDefinition {
 				NonNull:   true,
 			},
 		},
-		Arguments: []*ast.ArgumentDefinition{
-			&ast.ArgumentDefinition{
-				Name: "filter",
-				Type: &ast.Type{
-					NamedType: defn.Name + "Filter",
-					NonNull:   true,
-				},
-			},
-		},
+		Arguments: args,
 	}
 }
 
-func addQueryType(defn *ast.Definition) (flds []*ast.FieldDefinition) {
+func addQueryType(defn *ast.Definition, hasOrder, relay bool) (flds []*ast.FieldDefinition) {
 	flds = append(flds, createGetFld(defn))
-	flds = append(flds, createQryFld(defn))
+	if relay {
+		flds = append(flds, createRelayQryFld(defn, hasOrder))
+	} else {
+		flds = append(flds, createQryFld(defn, hasOrder))
+	}
 
 	return
 }
@@ -455,24 +1368,20 @@ func addMutationType(defn *ast.Definition) (flds []*ast.FieldDefinition) {
 	return
 }
 
-func getFilterField() ast.FieldList {
-	return []*ast.FieldDefinition{
-		&ast.FieldDefinition{
-			Name: "dgraph",
-			Type: &ast.Type{
-				NamedType: "String",
-			},
-		},
-	}
-}
-
 func getNonIDFields(schema *ast.Schema, defn *ast.Definition) ast.FieldList {
 	fldList := make([]*ast.FieldDefinition, 0)
-	for _, fld := range defn.Fields {
+	for _, fld := range fieldsIncludingInterfaces(schema, defn) {
 		if isIDField(defn, fld) {
 			continue
 		}
-		if schema.Types[fld.Type.Name()].Kind == ast.Object {
+		fldTypeDefn := schema.Types[fld.Type.Name()]
+		if fldTypeDefn != nil && fldTypeDefn.Kind == ast.Union {
+			// A union has no field common to all its members, so there's no
+			// sensible <Union>Ref to construct or update through - callers
+			// have to go via the concrete member type instead.
+			continue
+		}
+		if fldTypeDefn != nil && (fldTypeDefn.Kind == ast.Object || fldTypeDefn.Kind == ast.Interface) {
 			newDefn := &ast.FieldDefinition{
 				Name: fld.Name,
 			}
@@ -499,9 +1408,9 @@ func getNonIDFields(schema *ast.Schema, defn *ast.Definition) ast.FieldList {
 	return fldList
 }
 
-func getIDField(defn *ast.Definition) ast.FieldList {
+func getIDField(schema *ast.Schema, defn *ast.Definition) ast.FieldList {
 	fldList := make([]*ast.FieldDefinition, 0)
-	for _, fld := range defn.Fields {
+	for _, fld := range fieldsIncludingInterfaces(schema, defn) {
 		if isIDField(defn, fld) {
 			// Deepcopy is not required because we don't modify values other than nonull
 			newFld := *fld
@@ -582,7 +1491,7 @@ func genDirectiveArgumentsString(args ast.ArgumentList) string {
 
 	sch.WriteString("(")
 	for _, arg := range args {
-		direcArgs = append(direcArgs, fmt.Sprintf("%s:\"%s\"", arg.Name, arg.Value.Raw))
+		direcArgs = append(direcArgs, fmt.Sprintf("%s:%s", arg.Name, arg.Value.String()))
 	}
 
 	sort.Slice(direcArgs, func(i, j int) bool { return direcArgs[i] < direcArgs[j] })
@@ -617,7 +1526,22 @@ func genEnumString(typ *ast.Definition) string {
 }
 
 func genObjectString(typ *ast.Definition) string {
-	return fmt.Sprintf("type %s {\n%s}\n", typ.Name, genFieldsString(typ.Fields))
+	var implements string
+	if len(typ.Interfaces) > 0 {
+		implements = fmt.Sprintf(" implements %s", strings.Join(typ.Interfaces, " & "))
+	}
+	return fmt.Sprintf("type %s%s {\n%s}\n", typ.Name, implements, genFieldsString(typ.Fields))
+}
+
+func genInterfaceString(typ *ast.Definition) string {
+	return fmt.Sprintf("interface %s {\n%s}\n", typ.Name, genFieldsString(typ.Fields))
+}
+
+func genUnionString(typ *ast.Definition) string {
+	members := make([]string, len(typ.Types))
+	copy(members, typ.Types)
+	sort.Strings(members)
+	return fmt.Sprintf("union %s = %s\n", typ.Name, strings.Join(members, " | "))
 }
 
 func genScalarString(typ *ast.Definition) string {
@@ -666,7 +1590,7 @@ func genDirectivesDefnString(direcs map[string]*ast.DirectiveDefinition) string
 
 // Stringify returns entire schema in string format
 func Stringify(sch *ast.Schema) string {
-	var schStr, object, scalar, input, query, mutation, enum, direcDefn strings.Builder
+	var schStr, object, interfc, union, scalar, input, query, mutation, enum, direcDefn strings.Builder
 
 	if sch.Types == nil {
 		return ""
@@ -683,6 +1607,10 @@ func Stringify(sch *ast.Schema) string {
 
 		if typ.Kind == ast.Object {
 			object.WriteString(genObjectString(typ) + "\n")
+		} else if typ.Kind == ast.Interface {
+			interfc.WriteString(genInterfaceString(typ) + "\n")
+		} else if typ.Kind == ast.Union {
+			union.WriteString(genUnionString(typ) + "\n")
 		} else if typ.Kind == ast.Scalar {
 			scalar.WriteString(genScalarString(typ))
 		} else if typ.Kind == ast.InputObject {
@@ -706,6 +1634,10 @@ func Stringify(sch *ast.Schema) string {
 
 	schStr.WriteString("#######################\n# Generated Types\n#######################\n")
 	schStr.WriteString(object.String())
+	schStr.WriteString("#######################\n# Interface Definitions\n#######################\n")
+	schStr.WriteString(interfc.String())
+	schStr.WriteString("#######################\n# Union Definitions\n#######################\n")
+	schStr.WriteString(union.String())
 	schStr.WriteString("#######################\n# Scalar Definitions\n#######################\n")
 	schStr.WriteString(scalar.String())
 	schStr.WriteString("#######################\n# Directive Definitions\n#######################\n")
@@ -730,11 +1662,8 @@ func isIDField(defn *ast.Definition, fld *ast.FieldDefinition) bool {
 	return fld.Type.Name() == idTypeFor(defn)
 }
 
-// Then you can have functions like this to extract things from the directives
-// the functions are the behaviors that the rest of the code needs
-// ... generally better than encoding the behaviours into the remainder
-// of the code - particularly if it's in more than one spot.
-//
+// getInverseArgs pulls the "Type.field" argument a hasInverse directive was
+// given apart into its type and field components.
 func getInverseArgs(d *ast.Directive) (string, string, *gqlerror.Error) {
 	fldArg := d.Arguments.ForName(inverseFldArg)
 	if fldArg == nil {
@@ -748,70 +1677,185 @@ func getInverseArgs(d *ast.Directive) (string, string, *gqlerror.Error) {
 
 	splitVal := strings.Split(fldArg.Value.Raw, ".")
 	if len(splitVal) != 2 {
-		return "", "", gqlerror.ErrorPosf(fldArg.Position, "...nice error...")
+		return "", "", gqlerror.ErrorPosf(
+			fldArg.Position,
+			"hasInverse field argument must be of the form \"Type.field\", got %s",
+			fldArg.Value.Raw)
 	}
 
 	return splitVal[0], splitVal[1], nil
 }
 
-func getInverseDirective(dirs *ast.DirectiveList) *ast.Directive {
+func getInverseDirective(dirs ast.DirectiveList) *ast.Directive {
 	if dirs == nil {
 		return nil
 	}
 	return dirs.ForName(inverseName)
 }
 
-/* With ^^ this, checkHasInverseArgs can be simplified.  ATM part of it is:
-----
-if invFld.Directives == nil {
-	return gqlerror.ErrorPosf(
-		fld.Position, "Inverse of %s: %s, doesn't have inverse directive pointing back",
-		fld.Name, fldArg.Value.Raw,
-	)
+// validateHasInverse is the Directive.Validate for hasInverse: it checks
+// that the field named in the argument exists on the named type, and that
+// field itself carries a hasInverse pointing straight back at fld.
+func validateHasInverse(
+	doc *ast.SchemaDocument, typ *ast.Definition, fld *ast.FieldDefinition, dir *ast.Directive) *gqlerror.Error {
+
+	invTypeName, invFldName, gqlErr := getInverseArgs(dir)
+	if gqlErr != nil {
+		return gqlErr
+	}
+
+	invTyp := doc.Definitions.ForName(invTypeName)
+	if invTyp == nil {
+		return gqlerror.ErrorPosf(
+			dir.Position, "%s.%s: hasInverse field %s.%s: type %s doesn't exist.%s",
+			typ.Name, fld.Name, invTypeName, invFldName, invTypeName,
+			suggestionString(Suggest(invTypeName, definitionNames(doc))))
+	}
+
+	invFld := invTyp.Fields.ForName(invFldName)
+	if invFld == nil {
+		return gqlerror.ErrorPosf(
+			dir.Position, "%s.%s: hasInverse field %s.%s: field %s doesn't exist on type %s.%s",
+			typ.Name, fld.Name, invTypeName, invFldName, invFldName, invTypeName,
+			suggestionString(Suggest(invFldName, fieldNames(invTyp.Fields))))
+	}
+
+	invDir := getInverseDirective(invFld.Directives)
+	if invDir == nil {
+		return gqlerror.ErrorPosf(
+			dir.Position, "%s.%s: hasInverse field %s.%s: %s doesn't have an inverse directive "+
+				"pointing back to %s.%s", typ.Name, fld.Name, invTypeName, invFldName,
+			invFldName, typ.Name, fld.Name)
+	}
+
+	backTypeName, backFldName, gqlErr := getInverseArgs(invDir)
+	if gqlErr != nil {
+		return gqlErr
+	}
+	if backTypeName != typ.Name || backFldName != fld.Name {
+		return gqlerror.ErrorPosf(
+			dir.Position, "%s.%s: hasInverse field %s.%s: %s's hasInverse points to %s.%s, not "+
+				"back to %s.%s", typ.Name, fld.Name, invTypeName, invFldName, invFldName,
+			backTypeName, backFldName, typ.Name, fld.Name)
+	}
+
+	return nil
 }
 
-if invDirective := invFld.Directives.ForName(string(HASINVERSE)); invDirective != nil {
-	 if invFldArg := invDirective.Arguments.ForName(string(FIELD)); invFldArg != nil {
-					invSplitVal := strings.Split(invFldArg.Value.Raw, ".")
-					if len(invSplitVal) == 2 &&
-						!(invSplitVal[0] == typ.Name && invSplitVal[1] == fld.Name) {
-							........
-							........
-} else {
-	..same error as 3 if's above...
+const maxSuggestions = 5
+
+// definitionNames returns the name of every type/interface/union declared
+// in doc, for use as the candidate pool when suggesting a fix for a
+// misspelled type reference.
+func definitionNames(doc *ast.SchemaDocument) []string {
+	names := make([]string, 0, len(doc.Definitions))
+	for _, defn := range doc.Definitions {
+		names = append(names, defn.Name)
+	}
+	return names
 }
---------
 
-that becomes just
+func fieldNames(flds ast.FieldList) []string {
+	names := make([]string, 0, len(flds))
+	for _, fld := range flds {
+		names = append(names, fld.Name)
+	}
+	return names
+}
+
+// Suggest returns the names in options that are close enough to input -
+// by Levenshtein distance - to be worth offering as a "did you mean"
+// correction, nearest first.  A candidate qualifies if its distance is no
+// more than half the length of the longer of input/candidate (and always
+// at least 1, so single character names can still match each other).
+func Suggest(input string, options []string) []string {
+	type candidate struct {
+		name string
+		dist int
+	}
+
+	var candidates []candidate
+	for _, opt := range options {
+		dist := levenshtein(input, opt)
+		threshold := len(input) / 2
+		if t := len(opt) / 2; t > threshold {
+			threshold = t
+		}
+		if threshold < 1 {
+			threshold = 1
+		}
+
+		if dist <= threshold {
+			candidates = append(candidates, candidate{opt, dist})
+		}
+	}
 
-d := getInverseDirective(invFld.Directives)
-if d == nil { return ...nice error... }
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].dist < candidates[j].dist })
+	if len(candidates) > maxSuggestions {
+		candidates = candidates[:maxSuggestions]
+	}
 
-typ, fld := getInverseArgs(d)
-if (typ != typ.Name || fld != fld.Name) {
-	return ...nice error...
+	names := make([]string, len(candidates))
+	for i, c := range candidates {
+		names[i] = c.name
+	}
+	return names
 }
 
-The original checkHasInverseArgs mixes in some other validation, code to get
-args etc, and goes to 5 levels of nesting deep.  That makes the logic of what
-it's actually checking really hidden by all the other bits going on.
+// suggestionString formats names (as returned by Suggest) as a sentence to
+// tack onto the end of an error message, or "" if there's nothing to
+// suggest.
+func suggestionString(names []string) string {
+	if len(names) == 0 {
+		return ""
+	}
 
-This way, the function just becomes the logic that it cares about.
-*/
+	quoted := make([]string, len(names))
+	for i, n := range names {
+		quoted[i] = fmt.Sprintf("%q", n)
+	}
 
-/*
-We can probably do better than this too.
+	if len(quoted) == 1 {
+		return fmt.Sprintf(" Did you mean %s?", quoted[0])
+	}
 
- 	if direc.Name == string(HASINVERSE) {
-		return checkHasInverseArgs(typ, fld, direc, sch)
+	return fmt.Sprintf(
+		" Did you mean %s or %s?",
+		strings.Join(quoted[:len(quoted)-1], ", "), quoted[len(quoted)-1])
+}
+
+// levenshtein computes the edit distance between a and b via the standard
+// dynamic program over a [len(a)+1][len(b)+1]int table.
+func levenshtein(a, b string) int {
+	d := make([][]int, len(a)+1)
+	for i := range d {
+		d[i] = make([]int, len(b)+1)
+		d[i][0] = i
+	}
+	for j := 0; j <= len(b); j++ {
+		d[0][j] = j
 	}
 
-As the number of directives goes up, this becomes a long list of if's, all with
-a string compare to a constant and then a known fn call as the central part.
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			sub := d[i-1][j-1]
+			if a[i-1] != b[j-1] {
+				sub++
+			}
+			d[i][j] = minOf(d[i-1][j]+1, d[i][j-1]+1, sub)
+		}
+	}
 
-What if each directive in the supportedDirectives array also had a validation
-function.  Then all the nasty string compares against known constants all through
-the code can disapear, and we can just find the directive in the array and call
+	return d[len(a)][len(b)]
+}
 
-supporedDirectives[d].validate(...)
-*/
+func minOf(del, ins, sub int) int {
+	m := del
+	if ins < m {
+		m = ins
+	}
+	if sub < m {
+		m = sub
+	}
+	return m
+}
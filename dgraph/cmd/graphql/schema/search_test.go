@@ -0,0 +1,35 @@
+/*
+ * Copyright 2021 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schema
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestStringifyPreservesSearchByList(t *testing.T) {
+	sch := mustGenSchema(t, `
+		type Post { id: ID! title: String @search(by: ["regexp", "anyofterms"]) }
+	`)
+
+	out := Stringify(sch)
+	if !strings.Contains(out, `@search(by:["regexp","anyofterms"])`) {
+		t.Errorf("Stringify output dropped the @search by-list, got:\n%s", out)
+	}
+
+	validateGenerated(t, sch)
+}
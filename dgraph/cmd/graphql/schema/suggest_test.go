@@ -0,0 +1,73 @@
+/*
+ * Copyright 2021 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schema
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSuggest(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		options []string
+		want    []string
+	}{
+		{
+			name:    "single close match",
+			input:   "Humn",
+			options: []string{"Human", "Droid", "Starship"},
+			want:    []string{"Human"},
+		},
+		{
+			name:    "no close match",
+			input:   "Zzzzzz",
+			options: []string{"Human", "Droid", "Starship"},
+			want:    []string{},
+		},
+		{
+			name:    "exact match is suggested back with distance 0",
+			input:   "Human",
+			options: []string{"Human", "Droid"},
+			want:    []string{"Human"},
+		},
+		{
+			name:    "candidates ordered by distance",
+			input:   "Huma",
+			options: []string{"Humane", "Human", "Droid"},
+			want:    []string{"Human", "Humane"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Suggest(tt.input, tt.options)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Suggest(%q, %v) = %v, want %v", tt.input, tt.options, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSuggestCapsAtFiveCandidates(t *testing.T) {
+	options := []string{"aaaaa", "aaaab", "aaaac", "aaaad", "aaaae", "aaaaf"}
+	got := Suggest("aaaaa", options)
+	if len(got) > maxSuggestions {
+		t.Errorf("Suggest returned %d candidates, want at most %d", len(got), maxSuggestions)
+	}
+}